@@ -0,0 +1,177 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// ErrMevNotRunning is returned when a builder sends a bid, cancellation or
+	// simulation request while the validator isn't running the MEV subsystem.
+	ErrMevNotRunning = errors.New("mev is not running")
+
+	// ErrMevNotInTurn is returned when a bid arrives while the local validator
+	// isn't in turn to produce the next block.
+	ErrMevNotInTurn = errors.New("mev: not in-turn")
+
+	// ErrBidAlreadySealed is returned by CancelBid when the bid being
+	// cancelled has already been sealed into a block.
+	ErrBidAlreadySealed = errors.New("bid already sealed")
+
+	// ErrBundleFailed is returned when a bundle transaction that isn't listed
+	// in its Bundle's RevertingTxHashes reverts during simulation.
+	ErrBundleFailed = errors.New("bundle simulation failed: a non-reverting tx reverted")
+)
+
+// InvalidBidError is returned when a bid fails MevAPI's acceptance checks.
+type InvalidBidError struct {
+	message string
+}
+
+func (e *InvalidBidError) Error() string { return e.message }
+
+// NewInvalidBidError wraps message as an *InvalidBidError.
+func NewInvalidBidError(message string) error {
+	return &InvalidBidError{message: message}
+}
+
+// InvalidPayBidTxError is returned when PayBidTx/PayBidTxGasUsed don't agree
+// with each other or with the bid's BuilderFee.
+type InvalidPayBidTxError struct {
+	message string
+}
+
+func (e *InvalidPayBidTxError) Error() string { return e.message }
+
+// NewInvalidPayBidTxError wraps message as an *InvalidPayBidTxError.
+func NewInvalidPayBidTxError(message string) error {
+	return &InvalidPayBidTxError{message: message}
+}
+
+// BidIssue is reported by a validator back to the builder that sent a bid
+// which could not be processed, e.g. because it was dropped after the fact.
+type BidIssue struct {
+	BidHash common.Hash `json:"bidHash"`
+	Message string      `json:"message"`
+}
+
+// Bundle is an atomic group of builder-supplied transactions that must all
+// land in the block, in the given order, or be dropped together. A tx in the
+// bundle that isn't listed in RevertingTxHashes is not allowed to revert.
+type Bundle struct {
+	Txs               [][]byte      `json:"txs"`
+	RevertingTxHashes []common.Hash `json:"revertingTxHashes,omitempty"`
+	BlockNumber       uint64        `json:"blockNumber"`
+	MinTimestamp      uint64        `json:"minTimestamp,omitempty"`
+	MaxTimestamp      uint64        `json:"maxTimestamp,omitempty"`
+}
+
+// RawBid is the builder-signed payload of a bid, as defined by BEP-322.
+type RawBid struct {
+	BlockNumber     uint64         `json:"blockNumber"`
+	ParentHash      common.Hash    `json:"parentHash"`
+	Builder         common.Address `json:"builder"`
+	GasFee          *big.Int       `json:"gasFee"`
+	GasUsed         uint64         `json:"gasUsed"`
+	BuilderFee      *big.Int       `json:"builderFee,omitempty"`
+	Txs             [][]byte       `json:"txs"`
+	Bundles         []Bundle       `json:"bundles,omitempty"`
+	ReplacesBidHash common.Hash    `json:"replacesBidHash,omitempty"`
+	Signature       hexutil.Bytes  `json:"signature"`
+}
+
+// BidArgs is the parameter set accepted by SendBid/SimulateBid.
+type BidArgs struct {
+	RawBid          *RawBid       `json:"rawBid"`
+	PayBidTx        hexutil.Bytes `json:"payBidTx,omitempty"`
+	PayBidTxGasUsed uint64        `json:"payBidTxGasUsed,omitempty"`
+}
+
+// MevParams exposes the validator's current MEV configuration to builders.
+// It mirrors the knobs in ethconfig.MEVConfig, translated into the runtime
+// values MevAPI enforces.
+type MevParams struct {
+	MinGasFee                 *big.Int         `json:"minGasFee,omitempty"`
+	MinBuilderFee             *big.Int         `json:"minBuilderFee,omitempty"`
+	MaxBidsPerBuilderPerBlock int              `json:"maxBidsPerBuilderPerBlock,omitempty"`
+	BuilderAllowList          []common.Address `json:"builderAllowList,omitempty"`
+	BuilderDenyList           []common.Address `json:"builderDenyList,omitempty"`
+	BuilderReputationDecay    float64          `json:"builderReputationDecay,omitempty"`
+	Simulate                  bool             `json:"simulate"`
+}
+
+// BuilderStat is the rolling bid acceptance/revert record kept for a single
+// builder. Score is an exponential moving average of accepted-vs-rejected
+// outcomes, decayed by MevParams.BuilderReputationDecay on every update.
+type BuilderStat struct {
+	Accepted uint64  `json:"accepted"`
+	Rejected uint64  `json:"rejected"`
+	Score    float64 `json:"score"`
+	Updated  int64   `json:"updated"`
+}
+
+// sigHash returns the hash the builder signs over: the RawBid contents with
+// the signature itself excluded.
+func (b *RawBid) sigHash() common.Hash {
+	unsigned := *b
+	unsigned.Signature = nil
+	return rlpHash(&unsigned)
+}
+
+// Hash returns the canonical hash of the bid, signature included. Builders
+// and validators use it to identify a bid for lookup, replacement and
+// cancellation.
+func (b *RawBid) Hash() common.Hash {
+	return rlpHash(b)
+}
+
+// VerifyBuilderSignature recovers the address that signed the bid and checks
+// it against the bid's declared Builder field.
+func (b *RawBid) VerifyBuilderSignature() (common.Address, error) {
+	if len(b.Signature) != 65 {
+		return common.Address{}, fmt.Errorf("invalid bid signature length: %d", len(b.Signature))
+	}
+
+	pubkey, err := crypto.SigToPub(b.sigHash().Bytes(), b.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid bid signature: %w", err)
+	}
+
+	signer := crypto.PubkeyToAddress(*pubkey)
+	if signer != b.Builder {
+		return common.Address{}, fmt.Errorf("bid signature recovers to %v, not declared builder %v", signer, b.Builder)
+	}
+
+	return signer, nil
+}
+
+// Sign sets Builder to the address of key and Signature to key's signature
+// over the bid's contents, ready for VerifyBuilderSignature to check on the
+// receiving end. It's the client-side counterpart builders use to produce
+// the Builder/Signature fields before submitting a bid.
+func (b *RawBid) Sign(key *ecdsa.PrivateKey) error {
+	b.Builder = crypto.PubkeyToAddress(key.PublicKey)
+	b.Signature = nil
+
+	sig, err := crypto.Sign(b.sigHash().Bytes(), key)
+	if err != nil {
+		return err
+	}
+	b.Signature = sig
+	return nil
+}
+
+func rlpHash(x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
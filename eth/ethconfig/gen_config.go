@@ -73,6 +73,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		OverrideCancun          *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        uint64
+		MEV                     MEVConfig
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -131,6 +132,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
 	enc.BlobExtraReserve = c.BlobExtraReserve
+	enc.MEV = c.MEV
 	return &enc, nil
 }
 
@@ -193,6 +195,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		OverrideCancun          *uint64 `toml:",omitempty"`
 		OverrideVerkle          *uint64 `toml:",omitempty"`
 		BlobExtraReserve        *uint64
+		MEV                     *MEVConfig
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -366,5 +369,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobExtraReserve != nil {
 		c.BlobExtraReserve = *dec.BlobExtraReserve
 	}
+	if dec.MEV != nil {
+		c.MEV = *dec.MEV
+	}
 	return nil
 }
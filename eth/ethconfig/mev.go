@@ -0,0 +1,25 @@
+package ethconfig
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MEVConfig lets validator operators run a selective MEV auction instead of
+// the current all-or-nothing "MEV running" switch: bids can be floored,
+// builders can be allow/deny-listed, and well-behaved builders can be
+// favoured via a decaying reputation score.
+type MEVConfig struct {
+	MinBuilderFee             *big.Int         `toml:",omitempty"`
+	MinGasFee                 *big.Int         `toml:",omitempty"`
+	MaxBidsPerBuilderPerBlock int              `toml:",omitempty"`
+	BuilderAllowList          []common.Address `toml:",omitempty"`
+	BuilderDenyList           []common.Address `toml:",omitempty"`
+	BuilderReputationDecay    float64          `toml:",omitempty"`
+
+	// Simulate gates MevAPI.SimulateBid. It is set from the --mev.simulate
+	// flag, since a dry-run endpoint that traces EVM execution is otherwise a
+	// free way to load a validator node.
+	Simulate bool `toml:",omitempty"`
+}
@@ -4,37 +4,89 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
 )
 
 const (
 	TransferTxGasLimit = 25000
+
+	// simulateBidRateLimit caps how often a single caller may hit
+	// SimulateBid, which traces EVM execution and would otherwise be a free
+	// way to load a validator node.
+	simulateBidRateLimit = 5 // requests per second
+	simulateBidBurst     = 10
+
+	// maxSimulateLimiters bounds the per-caller limiter map so connection
+	// churn across many distinct source addresses can't grow it forever.
+	maxSimulateLimiters = 10000
+
+	// bestBidEventBuffer lets SubscribeBestBid tolerate a short stall in a
+	// single subscriber without blocking delivery to the others.
+	bestBidEventBuffer = 10
 )
 
 // MevAPI implements the interfaces that defined in the BEP-322.
 // It offers methods for the interaction between builders and validators.
 type MevAPI struct {
 	b Backend
+
+	simulateLimiterMu sync.Mutex
+	simulateLimiters  map[string]*limiterEntry
+
+	bidCountMu    sync.Mutex
+	bidCountBlock uint64
+	bidCounts     map[common.Address]int
 }
 
 // NewMevAPI creates a new MevAPI.
 func NewMevAPI(b Backend) *MevAPI {
-	return &MevAPI{b}
+	return &MevAPI{
+		b:                b,
+		simulateLimiters: make(map[string]*limiterEntry),
+		bidCounts:        make(map[common.Address]int),
+	}
+}
+
+// limiterEntry pairs a caller's rate limiter with the last time it was used,
+// so a full limiter map can evict the least-recently-used entry instead of an
+// arbitrary one.
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
 }
 
 // SendBid receives bid from the builders.
 // If mev is not running or bid is invalid, return error.
 // Otherwise, creates a builder bid for the given argument, submit it to the miner.
 func (m *MevAPI) SendBid(ctx context.Context, args types.BidArgs) (common.Hash, error) {
+	if err := m.validateBidArgs(&args); err != nil {
+		return common.Hash{}, err
+	}
+
+	return m.b.SendBid(ctx, &args)
+}
+
+// validateBidArgs runs the acceptance checks shared by SendBid and
+// SimulateBid: mev must be running and in-turn, the bid must target the
+// current parent and next block number, its bundles and gas accounting must
+// be well-formed, and the builder must clear the configured reserve and
+// allow/deny list.
+func (m *MevAPI) validateBidArgs(args *types.BidArgs) error {
 	if !m.b.MevRunning() {
-		return common.Hash{}, types.ErrMevNotRunning
+		return types.ErrMevNotRunning
 	}
 
 	if !m.b.MinerInTurn() {
-		return common.Hash{}, types.ErrMevNotInTurn
+		return types.ErrMevNotInTurn
 	}
 
 	var (
@@ -43,69 +95,411 @@ func (m *MevAPI) SendBid(ctx context.Context, args types.BidArgs) (common.Hash,
 	)
 
 	if rawBid == nil {
-		return common.Hash{}, types.NewInvalidBidError("rawBid should not be nil")
+		return types.NewInvalidBidError("rawBid should not be nil")
+	}
+
+	// Verify the builder's signature before anything below keys per-builder
+	// state (the allow/deny list and MaxBidsPerBuilderPerBlock counter) off
+	// of the caller-supplied Builder field. Otherwise SimulateBid, which never
+	// submits to the miner, would let an unauthenticated caller drain a
+	// targeted builder's per-block bid quota for free by repeatedly declaring
+	// that builder's address.
+	if _, err := rawBid.VerifyBuilderSignature(); err != nil {
+		return types.NewInvalidBidError(err.Error())
 	}
 
 	// only support bidding for the next block not for the future block
 	if rawBid.BlockNumber != currentHeader.Number.Uint64()+1 {
-		return common.Hash{}, types.NewInvalidBidError("stale block number or block in future")
+		return types.NewInvalidBidError("stale block number or block in future")
 	}
 
 	if rawBid.ParentHash != currentHeader.Hash() {
-		return common.Hash{}, types.NewInvalidBidError(
+		return types.NewInvalidBidError(
 			fmt.Sprintf("non-aligned parent hash: %v", currentHeader.Hash()))
 	}
 
 	if rawBid.GasFee == nil || rawBid.GasFee.Cmp(common.Big0) == 0 || rawBid.GasUsed == 0 {
-		return common.Hash{}, types.NewInvalidBidError("empty gasFee or empty gasUsed")
+		return types.NewInvalidBidError("empty gasFee or empty gasUsed")
+	}
+
+	if err := validateBundles(rawBid); err != nil {
+		return err
+	}
+
+	if err := m.enforceBuilderPolicy(rawBid); err != nil {
+		return err
 	}
 
 	if rawBid.BuilderFee != nil {
 		builderFee := rawBid.BuilderFee
 		if builderFee.Cmp(common.Big0) < 0 {
-			return common.Hash{}, types.NewInvalidBidError("builder fee should not be less than 0")
+			return types.NewInvalidBidError("builder fee should not be less than 0")
 		}
 
 		if builderFee.Cmp(common.Big0) == 0 {
 			if len(args.PayBidTx) != 0 || args.PayBidTxGasUsed != 0 {
-				return common.Hash{}, types.NewInvalidPayBidTxError("payBidTx should be nil when builder fee is 0")
+				return types.NewInvalidPayBidTxError("payBidTx should be nil when builder fee is 0")
 			}
 		}
 
 		if builderFee.Cmp(rawBid.GasFee) >= 0 {
-			return common.Hash{}, types.NewInvalidBidError("builder fee must be less than gas fee")
+			return types.NewInvalidBidError("builder fee must be less than gas fee")
 		}
 
 		if builderFee.Cmp(common.Big0) > 0 {
 			// payBidTx can be nil when validator and builder take some other settlement
 
 			if args.PayBidTxGasUsed > TransferTxGasLimit {
-				return common.Hash{}, types.NewInvalidBidError(
+				return types.NewInvalidBidError(
 					fmt.Sprintf("transfer tx gas used must be no more than %v", TransferTxGasLimit))
 			}
 
 			if (len(args.PayBidTx) == 0 && args.PayBidTxGasUsed != 0) ||
 				(len(args.PayBidTx) != 0 && args.PayBidTxGasUsed == 0) {
-				return common.Hash{}, types.NewInvalidPayBidTxError("non-aligned payBidTx and payBidTxGasUsed")
+				return types.NewInvalidPayBidTxError("non-aligned payBidTx and payBidTxGasUsed")
 			}
 		}
 	} else {
 		if len(args.PayBidTx) != 0 || args.PayBidTxGasUsed != 0 {
-			return common.Hash{}, types.NewInvalidPayBidTxError("payBidTx should be nil when builder fee is nil")
+			return types.NewInvalidPayBidTxError("payBidTx should be nil when builder fee is nil")
 		}
 	}
 
-	return m.b.SendBid(ctx, &args)
+	// ReplacesBidHash lets a builder atomically withdraw an outstanding bid
+	// while submitting its replacement, instead of racing a separate CancelBid
+	// call against the validator's selection window.
+	if rawBid.ReplacesBidHash != (common.Hash{}) && rawBid.ReplacesBidHash == rawBid.Hash() {
+		return types.NewInvalidBidError("replacesBidHash must not reference the bid itself")
+	}
+
+	return nil
+}
+
+// validateBundles enforces the atomic-bundle semantics SendBid accepts:
+// every bundle must target the bid's own block, carry at least one tx, list
+// only its own tx hashes in RevertingTxHashes, and must not reuse a nonce
+// already claimed by the bid's top-level tx list. Whether a non-reverting tx
+// actually reverts is a simulation concern handled by the backend, which
+// rejects the bid with ErrBundleFailed when that happens.
+func validateBundles(rawBid *types.RawBid) error {
+	if len(rawBid.Bundles) == 0 {
+		return nil
+	}
+
+	topLevelNonces := make(map[common.Address]map[uint64]bool)
+	for _, rawTx := range rawBid.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(rawTx); err != nil {
+			return types.NewInvalidBidError(fmt.Sprintf("invalid tx in bid: %v", err))
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return types.NewInvalidBidError(fmt.Sprintf("invalid tx signature in bid: %v", err))
+		}
+		if topLevelNonces[from] == nil {
+			topLevelNonces[from] = make(map[uint64]bool)
+		}
+		topLevelNonces[from][tx.Nonce()] = true
+	}
+
+	for i, bundle := range rawBid.Bundles {
+		if bundle.BlockNumber != rawBid.BlockNumber {
+			return types.NewInvalidBidError(
+				fmt.Sprintf("bundle %d targets block %d, bid is for block %d", i, bundle.BlockNumber, rawBid.BlockNumber))
+		}
+
+		if len(bundle.Txs) == 0 {
+			return types.NewInvalidBidError(fmt.Sprintf("bundle %d has no txs", i))
+		}
+
+		if bundle.MinTimestamp != 0 && bundle.MaxTimestamp != 0 && bundle.MaxTimestamp < bundle.MinTimestamp {
+			return types.NewInvalidBidError(fmt.Sprintf("bundle %d has maxTimestamp before minTimestamp", i))
+		}
+
+		bundleTxHashes := make(map[common.Hash]bool, len(bundle.Txs))
+		for _, rawTx := range bundle.Txs {
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(rawTx); err != nil {
+				return types.NewInvalidBidError(fmt.Sprintf("invalid tx in bundle %d: %v", i, err))
+			}
+
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err != nil {
+				return types.NewInvalidBidError(fmt.Sprintf("invalid tx signature in bundle %d: %v", i, err))
+			}
+
+			if topLevelNonces[from][tx.Nonce()] {
+				return types.NewInvalidBidError(
+					fmt.Sprintf("bundle %d reuses nonce %d already claimed by the top-level tx list", i, tx.Nonce()))
+			}
+
+			bundleTxHashes[tx.Hash()] = true
+		}
+
+		for _, h := range bundle.RevertingTxHashes {
+			if !bundleTxHashes[h] {
+				return types.NewInvalidBidError(
+					fmt.Sprintf("bundle %d lists reverting tx %v that is not part of the bundle", i, h))
+			}
+		}
+	}
+
+	return nil
+}
+
+// enforceBuilderPolicy rejects bids that fall below the validator's reserve
+// price or come from a builder that isn't allowed to bid, per the
+// ethconfig.MEV knobs surfaced through MevParams. It runs before the bid is
+// forwarded to the backend so a denied or underpriced bid never reaches the
+// miner's in-flight bid set.
+func (m *MevAPI) enforceBuilderPolicy(rawBid *types.RawBid) error {
+	params := m.b.MevParams()
+
+	if params.MinGasFee != nil && rawBid.GasFee.Cmp(params.MinGasFee) < 0 {
+		return types.NewInvalidBidError("gas fee below the configured reserve")
+	}
+
+	if rawBid.BuilderFee != nil && params.MinBuilderFee != nil && rawBid.BuilderFee.Cmp(params.MinBuilderFee) < 0 {
+		return types.NewInvalidBidError("builder fee below the configured reserve")
+	}
+
+	for _, denied := range params.BuilderDenyList {
+		if denied == rawBid.Builder {
+			return types.NewInvalidBidError(fmt.Sprintf("builder %v is not allowed to bid", rawBid.Builder))
+		}
+	}
+
+	if len(params.BuilderAllowList) != 0 {
+		allowed := false
+		for _, addr := range params.BuilderAllowList {
+			if addr == rawBid.Builder {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return types.NewInvalidBidError(fmt.Sprintf("builder %v is not on the allow list", rawBid.Builder))
+		}
+	}
+
+	if params.MaxBidsPerBuilderPerBlock > 0 {
+		if err := m.checkBidRate(rawBid.BlockNumber, rawBid.Builder, params.MaxBidsPerBuilderPerBlock); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBidRate enforces MaxBidsPerBuilderPerBlock: builder may submit at most
+// max bids for a given blockNumber. The counters reset whenever blockNumber
+// advances, so a builder's allowance refreshes every block rather than
+// accumulating across the chain's lifetime.
+func (m *MevAPI) checkBidRate(blockNumber uint64, builder common.Address, max int) error {
+	m.bidCountMu.Lock()
+	defer m.bidCountMu.Unlock()
+
+	if blockNumber != m.bidCountBlock {
+		m.bidCountBlock = blockNumber
+		m.bidCounts = make(map[common.Address]int)
+	}
+
+	if m.bidCounts[builder] >= max {
+		return types.NewInvalidBidError(
+			fmt.Sprintf("builder %v exceeded the maximum of %d bids for block %d", builder, max, blockNumber))
+	}
+
+	m.bidCounts[builder]++
+	return nil
+}
+
+// BuilderStats returns the rolling bid acceptance/revert ratio tracked per
+// builder, keyed by builder address. The backend persists this reputation to
+// an on-disk journal so it survives restarts.
+func (m *MevAPI) BuilderStats() map[common.Address]*types.BuilderStat {
+	return m.b.BuilderStats()
+}
+
+// CancelBid withdraws an outstanding bid identified by bidHash, provided
+// signature recovers to the same builder pubkey that signed the original bid.
+// If the bid has already been sealed into a block, ErrBidAlreadySealed is
+// returned. If the parent hash has since moved on, the backend no-ops and
+// returns nil, the same result as a successful cancellation; the two cases
+// are not distinguished to the caller.
+func (m *MevAPI) CancelBid(ctx context.Context, bidHash common.Hash, signature hexutil.Bytes) error {
+	if !m.b.MevRunning() {
+		return types.ErrMevNotRunning
+	}
+
+	return m.b.CancelBid(ctx, bidHash, signature)
 }
 
 func (m *MevAPI) BestBidGasFee(_ context.Context, parentHash common.Hash) *big.Int {
 	return m.b.BestBidGasFee(parentHash)
 }
 
+// BestBidEvent is pushed to SubscribeBestBid subscribers whenever the miner
+// promotes a new best bid for ParentHash.
+type BestBidEvent struct {
+	ParentHash  common.Hash    `json:"parentHash"`
+	BlockNumber uint64         `json:"blockNumber"`
+	GasFee      *big.Int       `json:"gasFee"`
+	BuilderFee  *big.Int       `json:"builderFee"`
+	Builder     common.Address `json:"builder"`
+	BidHash     common.Hash    `json:"bidHash"`
+	ReceivedAt  int64          `json:"receivedAt"`
+}
+
+// SubscribeBestBid notifies the subscriber every time the best bid for
+// parentHash changes, sparing builders from polling BestBidGasFee to decide
+// whether to top up their bid before the validator's cutoff.
+func (m *MevAPI) SubscribeBestBid(ctx context.Context, parentHash common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		// Buffered so a briefly slow subscriber doesn't immediately
+		// backpressure the miner's event feed; once the buffer is full, the
+		// feed's Send blocks until this loop drains it.
+		events := make(chan BestBidEvent, bestBidEventBuffer)
+		sub := m.b.SubscribeBestBidEvent(events)
+
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case event := <-events:
+				// Only deliver events for the parent hash this subscriber is
+				// watching, so bid data doesn't leak across forks.
+				if event.ParentHash != parentHash {
+					continue
+				}
+				notifier.Notify(rpcSub.ID, event)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 func (m *MevAPI) Params() *types.MevParams {
 	return m.b.MevParams()
 }
 
+// BidSimulationResult is returned by SimulateBid. It mirrors the fields the
+// miner computes when evaluating a live bid, without ever submitting the bid
+// to the builder selection pipeline.
+type BidSimulationResult struct {
+	GasUsed            uint64                            `json:"gasUsed"`
+	GasFee             *big.Int                          `json:"gasFee"`
+	BuilderFeeReceived *big.Int                          `json:"builderFeeReceived"`
+	StateDiff          map[common.Address]StateDiffItem  `json:"stateDiff"`
+	RevertReasons      []string                          `json:"revertReasons"`
+	WouldBeBest        bool                              `json:"wouldBeBest"`
+	CurrentBestGasFee  *big.Int                          `json:"currentBestGasFee"`
+}
+
+// StateDiffItem captures the balance/nonce delta of a single account touched
+// during bid simulation.
+type StateDiffItem struct {
+	Balance *big.Int `json:"balance,omitempty"`
+	Nonce   uint64   `json:"nonce,omitempty"`
+}
+
+// SimulateBid runs the same acceptance checks as SendBid, then executes the
+// bid's txs against a copy of the pending state on top of CurrentHeader,
+// without ever submitting the bid to the miner. This lets a builder discover
+// whether its assumed top-of-block state still holds before risking the
+// BuilderFee payment on a stale assumption.
+func (m *MevAPI) SimulateBid(ctx context.Context, args types.BidArgs) (*BidSimulationResult, error) {
+	if !m.b.MevParams().Simulate {
+		return nil, types.NewInvalidBidError("bid simulation is disabled, enable it with --mev.simulate")
+	}
+
+	if err := m.limitSimulateBid(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := m.validateBidArgs(&args); err != nil {
+		return nil, err
+	}
+
+	return m.b.SimulateBid(ctx, &args)
+}
+
+// limitSimulateBid enforces a per-caller rate limit on SimulateBid so the
+// dry-run endpoint can't be hammered for free traces.
+func (m *MevAPI) limitSimulateBid(ctx context.Context) error {
+	return m.allowSimulateBid(callerKey(ctx))
+}
+
+// callerKey identifies the caller for SimulateBid rate limiting: the source
+// IP with the ephemeral port stripped, so opening a fresh connection doesn't
+// buy a fresh burst allowance.
+func callerKey(ctx context.Context) string {
+	peerInfo := rpc.PeerInfoFromContext(ctx)
+	if peerInfo.RemoteAddr == "" {
+		return "unknown"
+	}
+	if host, _, err := net.SplitHostPort(peerInfo.RemoteAddr); err == nil {
+		return host
+	}
+	return peerInfo.RemoteAddr
+}
+
+// allowSimulateBid checks caller's rate limiter, creating one if this is its
+// first request. If the limiter map is already at capacity, the
+// least-recently-seen caller is evicted to make room, rather than an
+// arbitrary one, so a legitimate caller mid-burst isn't the one handed a
+// fresh allowance by having its limiter dropped.
+func (m *MevAPI) allowSimulateBid(caller string) error {
+	m.simulateLimiterMu.Lock()
+	now := time.Now()
+
+	entry, ok := m.simulateLimiters[caller]
+	if !ok {
+		if len(m.simulateLimiters) >= maxSimulateLimiters {
+			m.evictOldestLocked()
+		}
+		entry = &limiterEntry{limiter: rate.NewLimiter(rate.Limit(simulateBidRateLimit), simulateBidBurst)}
+		m.simulateLimiters[caller] = entry
+	}
+	entry.lastSeen = now
+	limiter := entry.limiter
+	m.simulateLimiterMu.Unlock()
+
+	if !limiter.Allow() {
+		return fmt.Errorf("simulateBid rate limit exceeded for %s", caller)
+	}
+
+	return nil
+}
+
+// evictOldestLocked drops the least-recently-seen entry from the limiter
+// map. The caller must hold m.simulateLimiterMu.
+func (m *MevAPI) evictOldestLocked() {
+	var oldestKey string
+	var oldestSeen time.Time
+
+	for k, entry := range m.simulateLimiters {
+		if oldestKey == "" || entry.lastSeen.Before(oldestSeen) {
+			oldestKey = k
+			oldestSeen = entry.lastSeen
+		}
+	}
+	if oldestKey != "" {
+		delete(m.simulateLimiters, oldestKey)
+	}
+}
+
 // Running returns true if mev is running
 func (m *MevAPI) Running() bool {
 	return m.b.MevRunning()
@@ -0,0 +1,259 @@
+package ethapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+var testChainID = big.NewInt(56)
+
+// signTx returns a signed dynamic fee tx for use as bid/bundle payload data,
+// and the binary encoding SendBid/SimulateBid expect.
+func signTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address) []byte {
+	t.Helper()
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1_000_000_000),
+		GasTipCap: big.NewInt(1_000_000_000),
+	})
+
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(testChainID), key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+	return raw
+}
+
+func TestValidateBundles(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	to := common.HexToAddress("0x000000000000000000000000000000000000ff")
+
+	t.Run("no bundles is valid", func(t *testing.T) {
+		rawBid := &types.RawBid{BlockNumber: 10}
+		if err := validateBundles(rawBid); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects bundle targeting the wrong block", func(t *testing.T) {
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Bundles: []types.Bundle{{
+				BlockNumber: 11,
+				Txs:         [][]byte{signTx(t, key, 0, to)},
+			}},
+		}
+		if err := validateBundles(rawBid); err == nil {
+			t.Fatal("expected an error for a mismatched bundle block number")
+		}
+	})
+
+	t.Run("rejects an empty bundle", func(t *testing.T) {
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Bundles:     []types.Bundle{{BlockNumber: 10}},
+		}
+		if err := validateBundles(rawBid); err == nil {
+			t.Fatal("expected an error for an empty bundle")
+		}
+	})
+
+	t.Run("rejects a bundle nonce already claimed at the top level", func(t *testing.T) {
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Txs:         [][]byte{signTx(t, key, 0, to)},
+			Bundles: []types.Bundle{{
+				BlockNumber: 10,
+				Txs:         [][]byte{signTx(t, key, 0, to)},
+			}},
+		}
+		if err := validateBundles(rawBid); err == nil {
+			t.Fatal("expected an error for a nonce reused between the bid and its bundle")
+		}
+	})
+
+	t.Run("accepts disjoint nonces", func(t *testing.T) {
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Txs:         [][]byte{signTx(t, key, 0, to)},
+			Bundles: []types.Bundle{{
+				BlockNumber: 10,
+				Txs:         [][]byte{signTx(t, key, 1, to)},
+			}},
+		}
+		if err := validateBundles(rawBid); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects a revertingTxHash outside the bundle", func(t *testing.T) {
+		bundleTx := signTx(t, key, 1, to)
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Bundles: []types.Bundle{{
+				BlockNumber:       10,
+				Txs:               [][]byte{bundleTx},
+				RevertingTxHashes: []common.Hash{common.HexToHash("0x01")},
+			}},
+		}
+		if err := validateBundles(rawBid); err == nil {
+			t.Fatal("expected an error for a revertingTxHash that isn't part of the bundle")
+		}
+	})
+
+	t.Run("rejects maxTimestamp before minTimestamp", func(t *testing.T) {
+		rawBid := &types.RawBid{
+			BlockNumber: 10,
+			Bundles: []types.Bundle{{
+				BlockNumber:  10,
+				Txs:          [][]byte{signTx(t, key, 0, to)},
+				MinTimestamp: 100,
+				MaxTimestamp: 50,
+			}},
+		}
+		if err := validateBundles(rawBid); err == nil {
+			t.Fatal("expected an error for maxTimestamp before minTimestamp")
+		}
+	})
+}
+
+// fakeBackend is a minimal Backend used to exercise enforceBuilderPolicy
+// without a real miner.
+type fakeBackend struct {
+	params *types.MevParams
+}
+
+func (f *fakeBackend) MevRunning() bool            { return true }
+func (f *fakeBackend) MinerInTurn() bool           { return true }
+func (f *fakeBackend) CurrentHeader() *types.Header { return &types.Header{} }
+func (f *fakeBackend) SendBid(context.Context, *types.BidArgs) (common.Hash, error) {
+	return common.Hash{}, nil
+}
+func (f *fakeBackend) CancelBid(context.Context, common.Hash, hexutil.Bytes) error { return nil }
+func (f *fakeBackend) SimulateBid(context.Context, *types.BidArgs) (*BidSimulationResult, error) {
+	return nil, nil
+}
+func (f *fakeBackend) BestBidGasFee(common.Hash) *big.Int { return new(big.Int) }
+func (f *fakeBackend) SubscribeBestBidEvent(chan<- BestBidEvent) event.Subscription {
+	return nil
+}
+func (f *fakeBackend) MevParams() *types.MevParams                         { return f.params }
+func (f *fakeBackend) BuilderStats() map[common.Address]*types.BuilderStat { return nil }
+
+func TestEnforceBuilderPolicy(t *testing.T) {
+	builder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	t.Run("rejects gas fee below the reserve", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{params: &types.MevParams{MinGasFee: big.NewInt(100)}})
+		rawBid := &types.RawBid{Builder: builder, BlockNumber: 1, GasFee: big.NewInt(50)}
+		if err := m.enforceBuilderPolicy(rawBid); err == nil {
+			t.Fatal("expected an error for a gas fee below the reserve")
+		}
+	})
+
+	t.Run("rejects a denied builder", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{params: &types.MevParams{BuilderDenyList: []common.Address{builder}}})
+		rawBid := &types.RawBid{Builder: builder, BlockNumber: 1, GasFee: big.NewInt(100)}
+		if err := m.enforceBuilderPolicy(rawBid); err == nil {
+			t.Fatal("expected an error for a denied builder")
+		}
+	})
+
+	t.Run("rejects a builder missing from a non-empty allow list", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{params: &types.MevParams{BuilderAllowList: []common.Address{other}}})
+		rawBid := &types.RawBid{Builder: builder, BlockNumber: 1, GasFee: big.NewInt(100)}
+		if err := m.enforceBuilderPolicy(rawBid); err == nil {
+			t.Fatal("expected an error for a builder not on the allow list")
+		}
+	})
+
+	t.Run("enforces MaxBidsPerBuilderPerBlock per block number", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{params: &types.MevParams{MaxBidsPerBuilderPerBlock: 2}})
+		rawBid := &types.RawBid{Builder: builder, BlockNumber: 1, GasFee: big.NewInt(100)}
+
+		if err := m.enforceBuilderPolicy(rawBid); err != nil {
+			t.Fatalf("expected 1st bid to be allowed, got %v", err)
+		}
+		if err := m.enforceBuilderPolicy(rawBid); err != nil {
+			t.Fatalf("expected 2nd bid to be allowed, got %v", err)
+		}
+		if err := m.enforceBuilderPolicy(rawBid); err == nil {
+			t.Fatal("expected 3rd bid in the same block to be rejected")
+		}
+
+		nextBlock := &types.RawBid{Builder: builder, BlockNumber: 2, GasFee: big.NewInt(100)}
+		if err := m.enforceBuilderPolicy(nextBlock); err != nil {
+			t.Fatalf("expected the per-block counter to reset on a new block, got %v", err)
+		}
+	})
+}
+
+func TestAllowSimulateBid(t *testing.T) {
+	t.Run("allows bursts up to the configured limit", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{})
+		for i := 0; i < simulateBidBurst; i++ {
+			if err := m.allowSimulateBid("caller-a"); err != nil {
+				t.Fatalf("request %d: expected no error, got %v", i, err)
+			}
+		}
+		if err := m.allowSimulateBid("caller-a"); err == nil {
+			t.Fatal("expected the burst allowance to be exhausted")
+		}
+	})
+
+	t.Run("evicts the least-recently-seen caller when the map is full", func(t *testing.T) {
+		m := NewMevAPI(&fakeBackend{})
+
+		if err := m.allowSimulateBid("stale"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		m.simulateLimiters["stale"].lastSeen = time.Now().Add(-time.Hour)
+
+		// Fill the map up to capacity with other, more recently seen callers.
+		for i := 0; i < maxSimulateLimiters-1; i++ {
+			key := common.BigToAddress(big.NewInt(int64(i))).Hex()
+			if err := m.allowSimulateBid(key); err != nil {
+				t.Fatalf("unexpected error filling the limiter map: %v", err)
+			}
+		}
+
+		if len(m.simulateLimiters) != maxSimulateLimiters {
+			t.Fatalf("expected the limiter map to be at capacity, got %d entries", len(m.simulateLimiters))
+		}
+
+		// One more distinct caller should evict "stale", not a random entry.
+		if err := m.allowSimulateBid("fresh"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := m.simulateLimiters["stale"]; ok {
+			t.Fatal("expected the least-recently-seen caller to be evicted")
+		}
+		if _, ok := m.simulateLimiters["fresh"]; !ok {
+			t.Fatal("expected the new caller to have been admitted")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Backend is the slice of node functionality MevAPI needs in order to serve
+// the BEP-322 builder/validator RPC surface. It is implemented by the miner's
+// bid simulator together with the chain accessors the rest of this package's
+// node Backend already exposes.
+type Backend interface {
+	// MevRunning reports whether the MEV subsystem is enabled.
+	MevRunning() bool
+	// MinerInTurn reports whether the local validator is in turn to produce
+	// the next block.
+	MinerInTurn() bool
+	// CurrentHeader returns the header of the current chain head.
+	CurrentHeader() *types.Header
+
+	// SendBid validates and forwards a bid to the miner's in-flight bid set.
+	SendBid(ctx context.Context, bid *types.BidArgs) (common.Hash, error)
+	// CancelBid withdraws a previously accepted bid, verifying that signature
+	// recovers to the original builder before evicting it.
+	CancelBid(ctx context.Context, bidHash common.Hash, signature hexutil.Bytes) error
+	// SimulateBid executes a bid's txs and bundles against a copy of the
+	// pending state without ever submitting it to the miner.
+	SimulateBid(ctx context.Context, bid *types.BidArgs) (*BidSimulationResult, error)
+
+	// BestBidGasFee returns the gas fee of the current best bid for
+	// parentHash, or zero if there is none.
+	BestBidGasFee(parentHash common.Hash) *big.Int
+	// SubscribeBestBidEvent registers ch to receive an event every time the
+	// miner promotes a new best bid.
+	SubscribeBestBidEvent(ch chan<- BestBidEvent) event.Subscription
+	// MevParams returns the validator's current MEV configuration.
+	MevParams() *types.MevParams
+	// BuilderStats returns the rolling per-builder acceptance/revert ratio.
+	BuilderStats() map[common.Address]*types.BuilderStat
+}
@@ -0,0 +1,76 @@
+package miner
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// bidJournal persists per-builder reputation to disk, the same way the
+// legacy tx pool journals local transactions, so a validator restart doesn't
+// reset every builder's acceptance/revert history back to zero.
+type bidJournal struct {
+	path string
+	mu   sync.Mutex
+}
+
+type bidJournalEntry struct {
+	Builder common.Address    `json:"builder"`
+	Stat    *types.BuilderStat `json:"stat"`
+}
+
+func newBidJournal(path string) *bidJournal {
+	return &bidJournal{path: path}
+}
+
+// load replays the journal and returns the most recent reputation snapshot
+// per builder. A missing journal file is not an error: it just means no
+// reputation has been recorded yet.
+func (j *bidJournal) load() (map[common.Address]*types.BuilderStat, error) {
+	stats := make(map[common.Address]*types.BuilderStat)
+
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return stats, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry bidJournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Tolerate a partially-written trailing line from an unclean
+			// shutdown rather than losing the rest of the journal.
+			continue
+		}
+		stats[entry.Builder] = entry.Stat
+	}
+	return stats, scanner.Err()
+}
+
+// record appends builder's latest reputation snapshot to the journal. The
+// journal is append-only; load() keeps only the last entry per builder.
+func (j *bidJournal) record(builder common.Address, stat *types.BuilderStat) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(bidJournalEntry{Builder: builder, Stat: stat})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	_, err = f.Write(line)
+	return err
+}
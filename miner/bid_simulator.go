@@ -0,0 +1,494 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errBidParentMoved is returned internally when a bid's parent hash is no
+// longer the chain head; callers translate it into the appropriate no-op.
+var errBidParentMoved = errors.New("bid's parent hash is no longer the chain head")
+
+// bidChain is the subset of blockchain access the bid simulator needs to
+// simulate a bid's txs and bundles against a throwaway copy of state.
+type bidChain interface {
+	core.ChainContext
+	CurrentHeader() *types.Header
+	StateAt(root common.Hash) (*state.StateDB, error)
+	Config() *params.ChainConfig
+}
+
+// bidEntry is a single outstanding bid tracked by the simulator.
+type bidEntry struct {
+	args   *types.BidArgs
+	hash   common.Hash
+	gasFee *big.Int // computed by simulate(), not the builder-declared RawBid.GasFee
+
+	sealed     bool
+	receivedAt time.Time
+}
+
+// bidSimulator is the miner-side counterpart of ethapi.MevAPI: it tracks the
+// best outstanding bid per parent hash, executes bundle/bid simulation
+// against a copy of the pending state, and persists per-builder reputation
+// across restarts. It implements ethapi.Backend's MEV-facing methods.
+type bidSimulator struct {
+	chain bidChain
+
+	mu      sync.RWMutex
+	running bool
+	inTurn  bool
+
+	bestBid    map[common.Hash]*bidEntry // parentHash -> best outstanding bid
+	bidsByHash map[common.Hash]*bidEntry // bidHash -> bid, across all parents
+	params     types.MevParams
+
+	statsMu      sync.Mutex
+	builderStats map[common.Address]*types.BuilderStat
+	journal      *bidJournal
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// newBidSimulator creates a bid simulator backed by chain. journalPath may be
+// empty, in which case builder reputation is kept in memory only.
+func newBidSimulator(chain bidChain, journalPath string) *bidSimulator {
+	bs := &bidSimulator{
+		chain:        chain,
+		bestBid:      make(map[common.Hash]*bidEntry),
+		bidsByHash:   make(map[common.Hash]*bidEntry),
+		builderStats: make(map[common.Address]*types.BuilderStat),
+	}
+
+	if journalPath != "" {
+		bs.journal = newBidJournal(journalPath)
+		stats, err := bs.journal.load()
+		if err != nil {
+			log.Warn("Failed to load bid reputation journal", "path", journalPath, "err", err)
+		} else {
+			bs.builderStats = stats
+		}
+	}
+
+	return bs
+}
+
+// SetMevParams updates the validator's MEV configuration. It's called by the
+// node on startup and whenever the operator reloads config.
+func (bs *bidSimulator) SetMevParams(p types.MevParams) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.params = p
+}
+
+// SetMevRunning flips whether the MEV subsystem is enabled.
+func (bs *bidSimulator) SetMevRunning(running bool) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.running = running
+}
+
+// SetInTurn flips whether the local validator is in turn to produce the next
+// block. The miner calls this as turn-ness changes.
+func (bs *bidSimulator) SetInTurn(inTurn bool) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.inTurn = inTurn
+}
+
+func (bs *bidSimulator) MevRunning() bool {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.running
+}
+
+func (bs *bidSimulator) MinerInTurn() bool {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	return bs.inTurn
+}
+
+func (bs *bidSimulator) CurrentHeader() *types.Header {
+	return bs.chain.CurrentHeader()
+}
+
+func (bs *bidSimulator) MevParams() *types.MevParams {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+	p := bs.params
+	return &p
+}
+
+// BestBidGasFee returns the gas fee of the current best bid for parentHash,
+// or zero if there is none outstanding.
+func (bs *bidSimulator) BestBidGasFee(parentHash common.Hash) *big.Int {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	best, ok := bs.bestBid[parentHash]
+	if !ok {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(best.gasFee)
+}
+
+// SubscribeBestBidEvent registers ch to be notified every time the best bid
+// for some parent hash changes.
+func (bs *bidSimulator) SubscribeBestBidEvent(ch chan<- ethapi.BestBidEvent) event.Subscription {
+	return bs.scope.Track(bs.feed.Subscribe(ch))
+}
+
+// BuilderStats returns a snapshot of the rolling per-builder acceptance/
+// revert ratio.
+func (bs *bidSimulator) BuilderStats() map[common.Address]*types.BuilderStat {
+	bs.statsMu.Lock()
+	defer bs.statsMu.Unlock()
+
+	out := make(map[common.Address]*types.BuilderStat, len(bs.builderStats))
+	for addr, stat := range bs.builderStats {
+		cp := *stat
+		out[addr] = &cp
+	}
+	return out
+}
+
+// SendBid simulates args against the current pending state, then, if it
+// succeeds, admits it to the in-flight bid set and promotes it to best bid
+// for its parent hash if it out-bids the current best.
+func (bs *bidSimulator) SendBid(ctx context.Context, args *types.BidArgs) (common.Hash, error) {
+	rawBid := args.RawBid
+
+	if _, err := rawBid.VerifyBuilderSignature(); err != nil {
+		return common.Hash{}, types.NewInvalidBidError(err.Error())
+	}
+
+	result, err := bs.simulate(args)
+	if err != nil {
+		bs.recordOutcome(rawBid.Builder, false)
+		return common.Hash{}, err
+	}
+
+	entry := &bidEntry{args: args, hash: rawBid.Hash(), gasFee: result.GasFee, receivedAt: time.Now()}
+
+	bs.mu.Lock()
+	var (
+		replacedParent  common.Hash
+		replacedBest    *bidEntry
+		replacedChanged bool
+	)
+	if rawBid.ReplacesBidHash != (common.Hash{}) {
+		var err error
+		replacedParent, replacedBest, replacedChanged, err = bs.evictLocked(rawBid.ReplacesBidHash, rawBid.Builder)
+		if err != nil && !errors.Is(err, errBidParentMoved) {
+			bs.mu.Unlock()
+			bs.recordOutcome(rawBid.Builder, false)
+			return common.Hash{}, err
+		}
+	}
+
+	bs.bidsByHash[entry.hash] = entry
+
+	var promoted bool
+	if best, ok := bs.bestBid[rawBid.ParentHash]; !ok || entry.gasFee.Cmp(best.gasFee) > 0 {
+		bs.bestBid[rawBid.ParentHash] = entry
+		promoted = true
+	}
+	bs.mu.Unlock()
+
+	// Report the replaced bid's eviction first, in the order it actually
+	// happened, before the new bid's own promotion (if any).
+	if replacedChanged {
+		bs.sendBestBidEvent(replacedParent, replacedBest)
+	}
+	if promoted {
+		bs.sendBestBidEvent(rawBid.ParentHash, entry)
+	}
+
+	bs.recordOutcome(rawBid.Builder, true)
+	return entry.hash, nil
+}
+
+// CancelBid withdraws bidHash, provided signature recovers to the same
+// builder pubkey that signed the original bid.
+func (bs *bidSimulator) CancelBid(ctx context.Context, bidHash common.Hash, signature hexutil.Bytes) error {
+	bs.mu.Lock()
+
+	entry, ok := bs.bidsByHash[bidHash]
+	if !ok {
+		// Unknown bid: already evicted, already sealed and pruned, or never
+		// existed on this node. Treat it as a no-op rather than erroring.
+		bs.mu.Unlock()
+		return nil
+	}
+
+	if len(signature) != 65 {
+		bs.mu.Unlock()
+		return types.NewInvalidBidError("invalid cancellation signature length")
+	}
+	pubkey, err := crypto.SigToPub(bidHash.Bytes(), signature)
+	if err != nil {
+		bs.mu.Unlock()
+		return types.NewInvalidBidError(fmt.Sprintf("invalid cancellation signature: %v", err))
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != entry.args.RawBid.Builder {
+		bs.mu.Unlock()
+		return types.NewInvalidBidError("cancellation signature does not match the bid's builder")
+	}
+
+	parent, newBest, bestChanged, err := bs.evictLocked(bidHash, entry.args.RawBid.Builder)
+	bs.mu.Unlock()
+
+	if err != nil {
+		if errors.Is(err, errBidParentMoved) {
+			// The chain has already moved past this bid's parent, so there's
+			// nothing left in the miner's in-flight set for the cancellation
+			// to act on. Log it and report success rather than bouncing an
+			// error back to a builder whose request is now moot.
+			log.Debug("Ignoring bid cancellation for a parent that's no longer the chain head", "bidHash", bidHash)
+			return nil
+		}
+		return err
+	}
+
+	if bestChanged {
+		bs.sendBestBidEvent(parent, newBest)
+	}
+	return nil
+}
+
+// sendBestBidEvent notifies SubscribeBestBid subscribers that the best bid
+// for parent has changed to best. best is nil when eviction left parent
+// without any outstanding bid at all, in which case subscribers are notified
+// with a zero gas fee so they stop holding out for a bid that's now gone.
+func (bs *bidSimulator) sendBestBidEvent(parent common.Hash, best *bidEntry) {
+	if best == nil {
+		bs.feed.Send(ethapi.BestBidEvent{
+			ParentHash: parent,
+			GasFee:     new(big.Int),
+		})
+		return
+	}
+
+	bs.feed.Send(ethapi.BestBidEvent{
+		ParentHash:  parent,
+		BlockNumber: best.args.RawBid.BlockNumber,
+		GasFee:      best.gasFee,
+		BuilderFee:  best.args.RawBid.BuilderFee,
+		Builder:     best.args.RawBid.Builder,
+		BidHash:     best.hash,
+		ReceivedAt:  best.receivedAt.Unix(),
+	})
+}
+
+// evictLocked removes bidHash from the in-flight bid set on behalf of
+// builder, demoting the parent's best bid to the next-highest outstanding bid
+// if necessary. The caller must hold bs.mu, and when bestChanged is true, must
+// call sendBestBidEvent(parent, newBest) after releasing it.
+func (bs *bidSimulator) evictLocked(bidHash common.Hash, builder common.Address) (parent common.Hash, newBest *bidEntry, bestChanged bool, err error) {
+	entry, ok := bs.bidsByHash[bidHash]
+	if !ok {
+		return common.Hash{}, nil, false, nil
+	}
+	if entry.sealed {
+		return common.Hash{}, nil, false, types.ErrBidAlreadySealed
+	}
+	if entry.args.RawBid.Builder != builder {
+		return common.Hash{}, nil, false, types.NewInvalidBidError("bid does not belong to this builder")
+	}
+
+	parent = entry.args.RawBid.ParentHash
+	if parent != bs.chain.CurrentHeader().Hash() {
+		// The chain has moved on since this bid was submitted; there's
+		// nothing left in the miner's in-flight set to evict.
+		delete(bs.bidsByHash, bidHash)
+		delete(bs.bestBid, parent)
+		return parent, nil, false, errBidParentMoved
+	}
+
+	delete(bs.bidsByHash, bidHash)
+
+	if best, ok := bs.bestBid[parent]; !ok || best != entry {
+		return parent, nil, false, nil
+	}
+	delete(bs.bestBid, parent)
+
+	// Re-promote the next best outstanding bid for this parent, if any.
+	for _, candidate := range bs.bidsByHash {
+		if candidate.args.RawBid.ParentHash != parent {
+			continue
+		}
+		cur, ok := bs.bestBid[parent]
+		if !ok || candidate.gasFee.Cmp(cur.gasFee) > 0 {
+			bs.bestBid[parent] = candidate
+		}
+	}
+
+	return parent, bs.bestBid[parent], true, nil
+}
+
+// recordOutcome updates builder's rolling acceptance/revert score and, if a
+// journal is configured, persists the new snapshot to disk.
+func (bs *bidSimulator) recordOutcome(builder common.Address, accepted bool) {
+	bs.mu.RLock()
+	decay := bs.params.BuilderReputationDecay
+	bs.mu.RUnlock()
+	if decay <= 0 || decay >= 1 {
+		decay = 0.9
+	}
+
+	bs.statsMu.Lock()
+	stat, ok := bs.builderStats[builder]
+	if !ok {
+		stat = &types.BuilderStat{}
+		bs.builderStats[builder] = stat
+	}
+
+	outcome := 0.0
+	if accepted {
+		stat.Accepted++
+		outcome = 1.0
+	} else {
+		stat.Rejected++
+	}
+	stat.Score = decay*stat.Score + (1-decay)*outcome
+	stat.Updated = time.Now().Unix()
+	snapshot := *stat
+	bs.statsMu.Unlock()
+
+	if bs.journal != nil {
+		if err := bs.journal.record(builder, &snapshot); err != nil {
+			log.Warn("Failed to persist bid reputation", "builder", builder, "err", err)
+		}
+	}
+}
+
+// SimulateBid runs the same simulation SendBid does, without ever admitting
+// the bid to the in-flight bid set.
+func (bs *bidSimulator) SimulateBid(ctx context.Context, args *types.BidArgs) (*ethapi.BidSimulationResult, error) {
+	return bs.simulate(args)
+}
+
+// simulate executes args' bundles (in order, tolerating only the reverts they
+// declare) followed by its top-level txs, against a fresh copy of the state
+// committed by the current chain head.
+func (bs *bidSimulator) simulate(args *types.BidArgs) (*ethapi.BidSimulationResult, error) {
+	rawBid := args.RawBid
+
+	header := bs.chain.CurrentHeader()
+	if rawBid.ParentHash != header.Hash() {
+		return nil, types.NewInvalidBidError("stale parent hash")
+	}
+
+	statedb, err := bs.chain.StateAt(header.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare bid simulation state: %w", err)
+	}
+
+	result := &ethapi.BidSimulationResult{
+		GasFee:            new(big.Int),
+		CurrentBestGasFee: bs.BestBidGasFee(rawBid.ParentHash),
+		StateDiff:         make(map[common.Address]ethapi.StateDiffItem),
+	}
+	touched := make(map[common.Address]struct{})
+
+	apply := func(rawTx []byte, reverting map[common.Hash]bool) error {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(rawTx); err != nil {
+			return types.NewInvalidBidError(fmt.Sprintf("invalid tx: %v", err))
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return types.NewInvalidBidError(fmt.Sprintf("invalid tx signature: %v", err))
+		}
+		touched[from] = struct{}{}
+		if to := tx.To(); to != nil {
+			touched[*to] = struct{}{}
+		}
+
+		gp := new(core.GasPool).AddGas(header.GasLimit)
+		usedGas := new(uint64)
+		receipt, err := core.ApplyTransaction(bs.chain.Config(), bs.chain, &header.Coinbase, gp, statedb, header, tx, usedGas, vm.Config{})
+		if err != nil {
+			return types.NewInvalidBidError(fmt.Sprintf("tx failed to apply: %v", err))
+		}
+
+		result.GasUsed += receipt.GasUsed
+		result.GasFee.Add(result.GasFee, new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), tx.GasPrice()))
+
+		if receipt.Status == types.ReceiptStatusFailed {
+			if !reverting[tx.Hash()] {
+				return types.ErrBundleFailed
+			}
+			result.RevertReasons = append(result.RevertReasons, tx.Hash().Hex())
+		}
+		return nil
+	}
+
+	for i, bundle := range rawBid.Bundles {
+		if bundle.MinTimestamp != 0 && header.Time < bundle.MinTimestamp {
+			return nil, fmt.Errorf("bundle %d: %w", i, types.NewInvalidBidError("block timestamp is before the bundle's minTimestamp"))
+		}
+		if bundle.MaxTimestamp != 0 && header.Time > bundle.MaxTimestamp {
+			return nil, fmt.Errorf("bundle %d: %w", i, types.NewInvalidBidError("block timestamp is after the bundle's maxTimestamp"))
+		}
+
+		reverting := make(map[common.Hash]bool, len(bundle.RevertingTxHashes))
+		for _, h := range bundle.RevertingTxHashes {
+			reverting[h] = true
+		}
+		for _, rawTx := range bundle.Txs {
+			if err := apply(rawTx, reverting); err != nil {
+				return nil, fmt.Errorf("bundle %d: %w", i, err)
+			}
+		}
+	}
+
+	for _, rawTx := range rawBid.Txs {
+		if err := apply(rawTx, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for addr := range touched {
+		result.StateDiff[addr] = ethapi.StateDiffItem{
+			Balance: statedb.GetBalance(addr),
+			Nonce:   statedb.GetNonce(addr),
+		}
+	}
+
+	// The builder declares GasFee/GasUsed up front so SendBid can enforce the
+	// reserve price and MaxBidsPerBuilderPerBlock before doing any EVM work,
+	// but only the values actually computed by executing the bid are trusted
+	// for the auction itself: a builder that declares more than it delivers
+	// must not be able to win on a number it never paid.
+	if rawBid.GasUsed != result.GasUsed {
+		return nil, types.NewInvalidBidError(
+			fmt.Sprintf("declared gasUsed %d does not match the %d computed by simulation", rawBid.GasUsed, result.GasUsed))
+	}
+	if rawBid.GasFee.Cmp(result.GasFee) != 0 {
+		return nil, types.NewInvalidBidError(
+			fmt.Sprintf("declared gasFee %v does not match the %v computed by simulation", rawBid.GasFee, result.GasFee))
+	}
+
+	result.BuilderFeeReceived = rawBid.BuilderFee
+	result.WouldBeBest = result.CurrentBestGasFee == nil || result.GasFee.Cmp(result.CurrentBestGasFee) > 0
+
+	return result, nil
+}
@@ -0,0 +1,227 @@
+package miner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testChainID matches testChain.Config(), so the signer simulate applies txs
+// with accepts the test transactions.
+var testChainID = params.TestChainConfig.ChainID
+
+// testChain is a minimal bidChain backed by a single committed block, enough
+// to let bidSimulator.simulate execute plain transfers against real state.
+type testChain struct {
+	header *types.Header
+	db     state.Database
+}
+
+func newTestChain(t *testing.T, alloc map[common.Address]*big.Int) *testChain {
+	t.Helper()
+
+	db := state.NewDatabase(rawdb.NewMemoryDatabase())
+	statedb, err := state.New(types.EmptyRootHash, db, nil)
+	if err != nil {
+		t.Fatalf("failed to create statedb: %v", err)
+	}
+	for addr, balance := range alloc {
+		statedb.SetBalance(addr, balance)
+	}
+	root, err := statedb.Commit(0, false)
+	if err != nil {
+		t.Fatalf("failed to commit statedb: %v", err)
+	}
+
+	return &testChain{
+		db: db,
+		header: &types.Header{
+			Number:   big.NewInt(100),
+			Time:     1000,
+			GasLimit: 30_000_000,
+			BaseFee:  big.NewInt(0),
+			Root:     root,
+		},
+	}
+}
+
+func (c *testChain) CurrentHeader() *types.Header { return c.header }
+func (c *testChain) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, c.db, nil)
+}
+func (c *testChain) Config() *params.ChainConfig                 { return params.TestChainConfig }
+func (c *testChain) Engine() consensus.Engine                    { return nil }
+func (c *testChain) GetHeader(common.Hash, uint64) *types.Header { return c.header }
+
+// signBid signs rawBid with key, filling in Builder and Signature, and
+// returns the bid's canonical hash.
+func signBid(t *testing.T, rawBid *types.RawBid, key *ecdsa.PrivateKey) common.Hash {
+	t.Helper()
+	if err := rawBid.Sign(key); err != nil {
+		t.Fatalf("failed to sign bid: %v", err)
+	}
+	return rawBid.Hash()
+}
+
+func signTransferTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address, value *big.Int) []byte {
+	t.Helper()
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     value,
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1_000_000_000),
+		GasTipCap: big.NewInt(1_000_000_000),
+	})
+
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(testChainID), key)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+	return raw
+}
+
+func newTestBid(t *testing.T, chain *testChain, key *ecdsa.PrivateKey, nonce uint64, to common.Address, value *big.Int) *types.BidArgs {
+	t.Helper()
+
+	gasFee := new(big.Int).Mul(big.NewInt(21000), big.NewInt(1_000_000_000))
+	rawBid := &types.RawBid{
+		BlockNumber: chain.header.Number.Uint64() + 1,
+		ParentHash:  chain.header.Hash(),
+		GasFee:      gasFee,
+		GasUsed:     21000,
+		Txs:         [][]byte{signTransferTx(t, key, nonce, to, value)},
+	}
+	signBid(t, rawBid, key)
+	return &types.BidArgs{RawBid: rawBid}
+}
+
+func TestBidSimulatorSendBidPromotesBestBid(t *testing.T) {
+	lowKey, _ := crypto.GenerateKey()
+	highKey, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x00000000000000000000000000000000000001")
+
+	lowAddr := crypto.PubkeyToAddress(lowKey.PublicKey)
+	highAddr := crypto.PubkeyToAddress(highKey.PublicKey)
+
+	chain := newTestChain(t, map[common.Address]*big.Int{
+		lowAddr:  new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e9)),
+		highAddr: new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e9)),
+	})
+	bs := newBidSimulator(chain, "")
+
+	events := make(chan ethapi.BestBidEvent, 10)
+	sub := bs.SubscribeBestBidEvent(events)
+	defer sub.Unsubscribe()
+
+	lowBid := newTestBid(t, chain, lowKey, 0, to, big.NewInt(1))
+	if _, err := bs.SendBid(context.Background(), lowBid); err != nil {
+		t.Fatalf("expected the first bid to be accepted, got %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Builder != lowAddr {
+			t.Fatalf("expected the first promotion event to name %v, got %v", lowAddr, ev.Builder)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a BestBidEvent for the first bid's promotion")
+	}
+
+	highBid := newTestBid(t, chain, highKey, 0, to, big.NewInt(1))
+	if _, err := bs.SendBid(context.Background(), highBid); err != nil {
+		t.Fatalf("expected the second bid to be accepted, got %v", err)
+	}
+
+	if got := bs.BestBidGasFee(chain.header.Hash()); got.Sign() == 0 {
+		t.Fatal("expected a non-zero best bid gas fee after two accepted bids")
+	}
+}
+
+func TestBidSimulatorCancelBidEvictsAndNotifies(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x00000000000000000000000000000000000001")
+
+	chain := newTestChain(t, map[common.Address]*big.Int{
+		addr: new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e9)),
+	})
+	bs := newBidSimulator(chain, "")
+
+	bid := newTestBid(t, chain, key, 0, to, big.NewInt(1))
+	hash, err := bs.SendBid(context.Background(), bid)
+	if err != nil {
+		t.Fatalf("failed to send bid: %v", err)
+	}
+
+	events := make(chan ethapi.BestBidEvent, 10)
+	sub := bs.SubscribeBestBidEvent(events)
+	defer sub.Unsubscribe()
+
+	sig, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		t.Fatalf("failed to sign cancellation: %v", err)
+	}
+	if err := bs.CancelBid(context.Background(), hash, sig); err != nil {
+		t.Fatalf("expected cancellation to succeed, got %v", err)
+	}
+
+	select {
+	case ev := <-events:
+		if ev.GasFee.Sign() != 0 {
+			t.Fatalf("expected a zero-gasFee event once the only bid is cancelled, got %v", ev.GasFee)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a BestBidEvent reporting the evicted best bid")
+	}
+
+	if got := bs.BestBidGasFee(chain.header.Hash()); got.Sign() != 0 {
+		t.Fatalf("expected no best bid after cancellation, got gasFee %v", got)
+	}
+
+	// Cancelling again is a no-op: the bid is already gone.
+	if err := bs.CancelBid(context.Background(), hash, sig); err != nil {
+		t.Fatalf("expected re-cancelling an already-evicted bid to no-op, got %v", err)
+	}
+}
+
+func TestBidSimulatorSimulateBidDoesNotMutateState(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x00000000000000000000000000000000000001")
+
+	chain := newTestChain(t, map[common.Address]*big.Int{
+		addr: new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(1e9)),
+	})
+	bs := newBidSimulator(chain, "")
+
+	bid := newTestBid(t, chain, key, 0, to, big.NewInt(1))
+	if _, err := bs.SimulateBid(context.Background(), bid); err != nil {
+		t.Fatalf("expected simulation to succeed, got %v", err)
+	}
+
+	if got := bs.BestBidGasFee(chain.header.Hash()); got.Sign() != 0 {
+		t.Fatalf("expected SimulateBid not to admit the bid to the in-flight set, got gasFee %v", got)
+	}
+	if len(bs.bidsByHash) != 0 {
+		t.Fatalf("expected SimulateBid not to track the bid by hash, got %d entries", len(bs.bidsByHash))
+	}
+}